@@ -0,0 +1,97 @@
+package memory_cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestCacheOnEvictedFiresOnDelete 验证Delete会触发OnEvicted回调,且回调在锁外执行
+func TestCacheOnEvictedFiresOnDelete(t *testing.T) {
+	c := newCache(0, time.Hour)
+	c.setValue("a", 1, 0)
+
+	var mu sync.Mutex
+	evicted := make(map[string]interface{})
+	c.OnEvicted(func(key string, value interface{}) {
+		// 回调在锁外执行时才能拿到缓存的写锁
+		if !c.mu.TryLock() {
+			t.Errorf("OnEvicted for %s ran while the cache lock was held", key)
+		} else {
+			c.mu.Unlock()
+		}
+		mu.Lock()
+		evicted[key] = value
+		mu.Unlock()
+	})
+
+	if err := c.Delete("a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	mu.Lock()
+	v, ok := evicted["a"]
+	mu.Unlock()
+	if !ok || v != 1 {
+		t.Fatalf("expected OnEvicted to fire with a=1, got %v, %v", v, ok)
+	}
+
+	if err := c.Delete("missing"); err == nil {
+		t.Fatalf("expected error deleting a missing key")
+	}
+}
+
+// TestCacheOnEvictedFiresForEveryFlushedEntry 验证Flush会为每个条目分别触发回调
+func TestCacheOnEvictedFiresForEveryFlushedEntry(t *testing.T) {
+	c := newCache(0, time.Hour)
+	c.setValue("a", 1, 0)
+	c.setValue("b", 2, 0)
+
+	var mu sync.Mutex
+	evicted := make(map[string]interface{})
+	c.OnEvicted(func(key string, value interface{}) {
+		mu.Lock()
+		evicted[key] = value
+		mu.Unlock()
+	})
+
+	c.Flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(evicted) != 2 || evicted["a"] != 1 || evicted["b"] != 2 {
+		t.Fatalf("expected both entries evicted by Flush, got %v", evicted)
+	}
+	if _, ok := c.getValue("a"); ok {
+		t.Fatalf("expected cache to be empty after Flush")
+	}
+}
+
+// TestCacheGroupOnEvictedAppliesToExistingAndFutureShards 验证CacheGroup.OnEvicted
+// 既应用到已存在的分片,也会被新建分片(addDefultCache)继承
+func TestCacheGroupOnEvictedAppliesToExistingAndFutureShards(t *testing.T) {
+	cg := NewCacheGroup(time.Hour)
+	cg.SetValue("existing", 1, 0)
+
+	var mu sync.Mutex
+	evicted := make(map[string]interface{})
+	cg.OnEvicted(func(key string, value interface{}) {
+		mu.Lock()
+		evicted[key] = value
+		mu.Unlock()
+	})
+
+	cg.SetValue("future", 2, 0)
+
+	if err := cg.Delete("existing"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := cg.Delete("future"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if evicted["existing"] != 1 || evicted["future"] != 2 {
+		t.Fatalf("expected OnEvicted to fire for both existing and future shards, got %v", evicted)
+	}
+}
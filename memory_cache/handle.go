@@ -0,0 +1,23 @@
+package memory_cache
+
+import (
+	"runtime"
+	"time"
+)
+
+// Handle 包裹一个独立创建的 Cache,janitor协程只持有内部的*Cache,
+// 因此Handle不再被引用时可以被GC回收,触发runtime.SetFinalizer自动停止janitor
+type Handle struct {
+	*Cache
+}
+
+// New 创建一个独立的 Cache 并返回其 Handle,调用方无需手动调用 Close
+// 也可以随时显式调用 Handle.Close 提前停止janitor
+func New(expiration, cleanupInterval time.Duration) *Handle {
+	c := newCache(expiration, cleanupInterval)
+	h := &Handle{c}
+	runtime.SetFinalizer(h, func(h *Handle) {
+		h.Close()
+	})
+	return h
+}
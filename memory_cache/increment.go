@@ -0,0 +1,449 @@
+package memory_cache
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrKeyExists    = errors.New("key exists")    //key已存在且未过期
+	ErrKeyNotFound  = errors.New("key not exist") //key不存在或已过期
+	ErrTypeMismatch = errors.New("type mismatch") //value类型不匹配
+)
+
+// Add 仅在 key 不存在或已过期时写入,否则返回 ErrKeyExists
+func (this *Cache) Add(key string, value interface{}, d time.Duration) error {
+	this.mu.Lock()
+	if v, ok := this.items[key]; ok {
+		now := time.Now().UnixNano()
+		if v.expiration == 0 || v.expiration > now {
+			this.mu.Unlock()
+			return ErrKeyExists
+		}
+	}
+	v := item{object: value}
+	if d > 0 {
+		v.expiration = time.Now().Add(d).UnixNano()
+	}
+	evicted := this.setItemLocked(key, v)
+	cb := this.onEvicted
+	this.mu.Unlock()
+	if cb != nil {
+		for k, ev := range evicted {
+			cb(k, ev)
+		}
+	}
+	return nil
+}
+
+// Replace 仅在 key 存在且未过期时覆盖,否则返回 ErrKeyNotFound
+func (this *Cache) Replace(key string, value interface{}, d time.Duration) error {
+	this.mu.Lock()
+	v, ok := this.items[key]
+	if !ok {
+		this.mu.Unlock()
+		return ErrKeyNotFound
+	}
+	now := time.Now().UnixNano()
+	if v.expiration > 0 && v.expiration < now {
+		this.mu.Unlock()
+		return ErrKeyNotFound
+	}
+	nv := item{object: value}
+	if d > 0 {
+		nv.expiration = time.Now().Add(d).UnixNano()
+	}
+	evicted := this.setItemLocked(key, nv)
+	cb := this.onEvicted
+	this.mu.Unlock()
+	if cb != nil {
+		for k, ev := range evicted {
+			cb(k, ev)
+		}
+	}
+	return nil
+}
+
+func (this *Cache) getValidLocked(key string) (item, bool) {
+	v, ok := this.items[key]
+	if !ok {
+		return item{}, false
+	}
+	now := time.Now().UnixNano()
+	if v.expiration > 0 && v.expiration < now {
+		return item{}, false
+	}
+	return v, true
+}
+
+func (this *Cache) IncrementInt(key string, n int) (int, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(int)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv += n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) IncrementInt32(key string, n int32) (int32, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(int32)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv += n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) IncrementInt64(key string, n int64) (int64, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(int64)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv += n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) IncrementUint(key string, n uint) (uint, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(uint)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv += n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) IncrementUint32(key string, n uint32) (uint32, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(uint32)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv += n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) IncrementUint64(key string, n uint64) (uint64, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(uint64)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv += n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) IncrementFloat32(key string, n float32) (float32, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(float32)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv += n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) IncrementFloat64(key string, n float64) (float64, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(float64)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv += n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) DecrementInt(key string, n int) (int, error) {
+	return this.IncrementInt(key, -n)
+}
+
+func (this *Cache) DecrementInt32(key string, n int32) (int32, error) {
+	return this.IncrementInt32(key, -n)
+}
+
+func (this *Cache) DecrementInt64(key string, n int64) (int64, error) {
+	return this.IncrementInt64(key, -n)
+}
+
+func (this *Cache) DecrementUint(key string, n uint) (uint, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(uint)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv -= n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) DecrementUint32(key string, n uint32) (uint32, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(uint32)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv -= n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) DecrementUint64(key string, n uint64) (uint64, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	v, ok := this.getValidLocked(key)
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	rv, ok := v.object.(uint64)
+	if !ok {
+		return 0, ErrTypeMismatch
+	}
+	rv -= n
+	v.object = rv
+	this.touchLRU(key, &v)
+	this.items[key] = v
+	return rv, nil
+}
+
+func (this *Cache) DecrementFloat32(key string, n float32) (float32, error) {
+	return this.IncrementFloat32(key, -n)
+}
+
+func (this *Cache) DecrementFloat64(key string, n float64) (float64, error) {
+	return this.IncrementFloat64(key, -n)
+}
+
+// Add 仅在 key 不存在或已过期时写入,否则返回 ErrKeyExists
+func (this *CacheGroup) Add(key string, value interface{}, d time.Duration) error {
+	c, ok := this.getCache(key)
+	if !ok {
+		c = this.addDefultCache(key)
+	}
+	return c.Add(key, value, d)
+}
+
+// Replace 仅在 key 存在且未过期时覆盖,否则返回 ErrKeyNotFound
+func (this *CacheGroup) Replace(key string, value interface{}, d time.Duration) error {
+	c, ok := this.getCache(key)
+	if !ok {
+		return ErrKeyNotFound
+	}
+	return c.Replace(key, value, d)
+}
+
+func (this *CacheGroup) requireCache(key string) (*Cache, error) {
+	c, ok := this.getCache(key)
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return c, nil
+}
+
+func (this *CacheGroup) IncrementInt(key string, n int) (int, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.IncrementInt(key, n)
+}
+
+func (this *CacheGroup) IncrementInt32(key string, n int32) (int32, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.IncrementInt32(key, n)
+}
+
+func (this *CacheGroup) IncrementInt64(key string, n int64) (int64, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.IncrementInt64(key, n)
+}
+
+func (this *CacheGroup) IncrementUint(key string, n uint) (uint, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.IncrementUint(key, n)
+}
+
+func (this *CacheGroup) IncrementUint32(key string, n uint32) (uint32, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.IncrementUint32(key, n)
+}
+
+func (this *CacheGroup) IncrementUint64(key string, n uint64) (uint64, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.IncrementUint64(key, n)
+}
+
+func (this *CacheGroup) IncrementFloat32(key string, n float32) (float32, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.IncrementFloat32(key, n)
+}
+
+func (this *CacheGroup) IncrementFloat64(key string, n float64) (float64, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.IncrementFloat64(key, n)
+}
+
+func (this *CacheGroup) DecrementInt(key string, n int) (int, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.DecrementInt(key, n)
+}
+
+func (this *CacheGroup) DecrementInt32(key string, n int32) (int32, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.DecrementInt32(key, n)
+}
+
+func (this *CacheGroup) DecrementInt64(key string, n int64) (int64, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.DecrementInt64(key, n)
+}
+
+func (this *CacheGroup) DecrementUint(key string, n uint) (uint, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.DecrementUint(key, n)
+}
+
+func (this *CacheGroup) DecrementUint32(key string, n uint32) (uint32, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.DecrementUint32(key, n)
+}
+
+func (this *CacheGroup) DecrementUint64(key string, n uint64) (uint64, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.DecrementUint64(key, n)
+}
+
+func (this *CacheGroup) DecrementFloat32(key string, n float32) (float32, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.DecrementFloat32(key, n)
+}
+
+func (this *CacheGroup) DecrementFloat64(key string, n float64) (float64, error) {
+	c, err := this.requireCache(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.DecrementFloat64(key, n)
+}
@@ -0,0 +1,124 @@
+package memory_cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheAddReplaceErrors(t *testing.T) {
+	c := newCache(0, time.Hour)
+
+	if err := c.Add("a", 1, 0); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := c.Add("a", 2, 0); err != ErrKeyExists {
+		t.Fatalf("expected ErrKeyExists re-adding a live key, got %v", err)
+	}
+
+	if err := c.Replace("missing", 1, 0); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound replacing a missing key, got %v", err)
+	}
+	if err := c.Replace("a", 2, 0); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if v, ok := c.getValue("a"); !ok || v != 2 {
+		t.Fatalf("expected a=2 after Replace, got %v, %v", v, ok)
+	}
+
+	c.setValue("expired", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if err := c.Add("expired", 2, 0); err != nil {
+		t.Fatalf("expected Add to succeed over an expired key, got %v", err)
+	}
+	if v, ok := c.getValue("expired"); !ok || v != 2 {
+		t.Fatalf("expected expired=2 after Add, got %v, %v", v, ok)
+	}
+}
+
+func TestCacheIncrementDecrement(t *testing.T) {
+	c := newCache(0, time.Hour)
+	c.setValue("n", 10, 0)
+
+	if v, err := c.IncrementInt("n", 5); err != nil || v != 15 {
+		t.Fatalf("expected IncrementInt to return 15, got %v, %v", v, err)
+	}
+	if v, err := c.DecrementInt("n", 3); err != nil || v != 12 {
+		t.Fatalf("expected DecrementInt to return 12, got %v, %v", v, err)
+	}
+
+	if _, err := c.IncrementInt("missing", 1); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound incrementing a missing key, got %v", err)
+	}
+
+	c.setValue("s", "not a number", 0)
+	if _, err := c.IncrementInt("s", 1); err != ErrTypeMismatch {
+		t.Fatalf("expected ErrTypeMismatch incrementing a non-int value, got %v", err)
+	}
+
+	c.setValue("f64", float64(1.5), 0)
+	if v, err := c.IncrementFloat64("f64", 0.5); err != nil || v != 2.0 {
+		t.Fatalf("expected IncrementFloat64 to return 2.0, got %v, %v", v, err)
+	}
+	if v, err := c.DecrementFloat64("f64", 1.0); err != nil || v != 1.0 {
+		t.Fatalf("expected DecrementFloat64 to return 1.0, got %v, %v", v, err)
+	}
+
+	c.setValue("u64", uint64(10), 0)
+	if v, err := c.DecrementUint64("u64", 4); err != nil || v != 6 {
+		t.Fatalf("expected DecrementUint64 to return 6, got %v, %v", v, err)
+	}
+}
+
+// TestCacheIncrementIntConcurrentDoesNotLoseWrites 验证并发IncrementInt不会丢失写入,
+// 这是chunk0-4引入原子计数接口的核心诉求
+func TestCacheIncrementIntConcurrentDoesNotLoseWrites(t *testing.T) {
+	c := newCache(0, time.Hour)
+	c.setValue("counter", 0, 0)
+
+	const goroutines = 50
+	const incrementsPerGoroutine = 100
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < incrementsPerGoroutine; j++ {
+				if _, err := c.IncrementInt("counter", 1); err != nil {
+					t.Errorf("IncrementInt failed: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := goroutines * incrementsPerGoroutine
+	if v, ok := c.getValue("counter"); !ok || v != want {
+		t.Fatalf("expected counter=%d after concurrent increments, got %v, %v", want, v, ok)
+	}
+}
+
+func TestCacheGroupAddReplaceIncrementDelegates(t *testing.T) {
+	cg := NewCacheGroup(time.Hour)
+
+	if err := cg.Add("n", 10, 0); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := cg.Add("n", 99, 0); err != ErrKeyExists {
+		t.Fatalf("expected ErrKeyExists, got %v", err)
+	}
+	if err := cg.Replace("missing", 1, 0); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound replacing an unknown shard, got %v", err)
+	}
+
+	if v, err := cg.IncrementInt("n", 5); err != nil || v != 15 {
+		t.Fatalf("expected IncrementInt to return 15, got %v, %v", v, err)
+	}
+	if v, err := cg.DecrementInt32("n", 1); err == nil {
+		t.Fatalf("expected ErrTypeMismatch decrementing an int value as int32, got %v, nil", v)
+	}
+	if v, err := cg.DecrementInt("n", 5); err != nil || v != 10 {
+		t.Fatalf("expected DecrementInt to return 10, got %v, %v", v, err)
+	}
+}
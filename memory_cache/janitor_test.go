@@ -0,0 +1,44 @@
+package memory_cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestJanitorSetIntervalConcurrentWithRun 覆盖 SetJanitorInterval 在 janitor.run 刚启动时
+// 并发调用的情况:ticker 现在在 newJanitor 中同步创建,run() 不再对 this.ticker 写入,
+// 所以这里不应该出现对未初始化 ticker 的并发读写。
+func TestJanitorSetIntervalConcurrentWithRun(t *testing.T) {
+	c := newCache(0, time.Hour)
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.SetJanitorInterval(time.Millisecond)
+		}()
+	}
+	wg.Wait()
+}
+
+// TestJanitorCloseStopsRunGoroutine 验证 Close 之后 janitor 的清理协程会退出
+func TestJanitorCloseStopsRunGoroutine(t *testing.T) {
+	c := newCache(0, time.Millisecond)
+	c.setValue("a", 1, time.Millisecond)
+	c.Close()
+	// run()在stop关闭后返回
+	select {
+	case <-c.janitor.stop:
+	default:
+		t.Fatalf("expected Close to close the janitor stop channel")
+	}
+	time.Sleep(10 * time.Millisecond)
+	// Close之后janitor已经退出,不会再清理过期条目,但Get/Set仍可正常使用
+	c.setValue("b", 2, 0)
+	if v, ok := c.getValue("b"); !ok || v != 2 {
+		t.Fatalf("expected cache to remain usable after Close, got %v, %v", v, ok)
+	}
+}
@@ -0,0 +1,63 @@
+package memory_cache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestLRUAddReplaceLoadDoNotPanic 覆盖所有写路径(Add/Replace/Load)在 MaxEntries>0
+// 时都应维护 item.elem 的不变式,而不是直接在后续的 getValue/setValue 中触发
+// this.lru.MoveToFront(nil) 的空指针 panic
+func TestLRUAddReplaceLoadDoNotPanic(t *testing.T) {
+	c := newCache(0, time.Hour, 10)
+
+	if err := c.Add("a", 1, 0); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, ok := c.getValue("a"); !ok {
+		t.Fatalf("expected a to be present after Add")
+	}
+	c.setValue("a", 2, 0) // 触发 getValue 之后的再写,曾经会panic
+
+	if err := c.Add("b", 1, 0); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := c.Replace("b", 2, 0); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if v, ok := c.getValue("b"); !ok || v != 2 {
+		t.Fatalf("expected b=2 after Replace, got %v, %v", v, ok)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := c.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v, ok := c.getValue("a"); !ok || v != 2 {
+		t.Fatalf("expected a=2 after Load round trip, got %v, %v", v, ok)
+	}
+	c.setValue("a", 3, 0) // 曾经在Load写入的条目上panic
+}
+
+// TestLRUEvictsOverCapacity 验证超出 MaxEntries 时会淘汰最久未使用的条目
+func TestLRUEvictsOverCapacity(t *testing.T) {
+	c := newCache(0, time.Hour, 2)
+	c.setValue("a", 1, 0)
+	c.setValue("b", 2, 0)
+	c.getValue("a") // a 变为最近使用
+	c.setValue("c", 3, 0)
+
+	if _, ok := c.getValue("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+	if _, ok := c.getValue("a"); !ok {
+		t.Fatalf("expected a to still be present")
+	}
+	if _, ok := c.getValue("c"); !ok {
+		t.Fatalf("expected c to be present")
+	}
+}
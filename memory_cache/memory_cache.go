@@ -1,6 +1,7 @@
 package memory_cache
 
 import (
+	"container/list"
 	"errors"
 	"sync"
 	"time"
@@ -13,31 +14,72 @@ const (
 )
 
 type item struct {
-	object     interface{} //value
-	expiration int64       //过期时间
+	object     interface{}  //value
+	expiration int64        //过期时间
+	elem       *list.Element //在lru链表中的位置,MaxEntries<=0时不使用
 }
 
 type janitor struct {
 	stop     chan bool     //停止
+	mu       sync.Mutex    //保护interval和ticker.Reset,串行化并发的SetJanitorInterval调用;run()不持有该锁
 	interval time.Duration //间隔时间
+	ticker   *time.Ticker  //定时器,在newJanitor中创建,run()启动前就绪
+	once     sync.Once     //保证stop只被关闭一次
 }
 
 func (this *janitor) run(c *Cache) {
-	ticker := time.NewTicker(this.interval)
+	defer this.ticker.Stop()
 	for {
-		now := time.Now().UnixNano()
 		select {
-		case <-ticker.C:
+		case <-this.ticker.C:
+			now := time.Now().UnixNano()
 			c.mu.Lock()
+			cb := c.onEvicted
+			var evicted map[string]interface{}
 			for k, v := range c.items {
 				if v.expiration > 0 && v.expiration < now {
 					delete(c.items, k)
+					if c.maxEntries > 0 && v.elem != nil {
+						c.lru.Remove(v.elem)
+					}
+					if cb != nil {
+						if evicted == nil {
+							evicted = make(map[string]interface{})
+						}
+						evicted[k] = v.object
+					}
 				}
 			}
 			c.mu.Unlock()
+			if cb != nil {
+				for k, v := range evicted {
+					cb(k, v)
+				}
+			}
+		case <-this.stop:
+			return
 		}
 	}
 }
+
+// setInterval 在运行期间调整清理间隔,可以在run()启动前后的任意时刻调用
+func (this *janitor) setInterval(d time.Duration) {
+	if d <= 0 {
+		d = defultInterval
+	}
+	this.mu.Lock()
+	this.interval = d
+	this.ticker.Reset(d)
+	this.mu.Unlock()
+}
+
+// close 关闭stop channel,多次调用是安全的
+func (this *janitor) close() {
+	this.once.Do(func() {
+		close(this.stop)
+	})
+}
+
 func newJanitor(d time.Duration) *janitor {
 	if d <= 0 {
 		d = defultInterval
@@ -45,21 +87,30 @@ func newJanitor(d time.Duration) *janitor {
 	return &janitor{
 		stop:     make(chan bool),
 		interval: d,
+		ticker:   time.NewTicker(d),
 	}
 }
 
 type Cache struct {
-	items      map[string]item //缓存数据
-	mu         sync.RWMutex    //读写锁
-	janitor    *janitor        //定时删除过期缓存
-	expiration int64           //整个缓存过期时间
+	items      map[string]item                    //缓存数据
+	mu         sync.RWMutex                        //读写锁
+	janitor    *janitor                            //定时删除过期缓存
+	expiration int64                               //整个缓存过期时间
+	onEvicted  func(key string, value interface{}) //条目被清除时的回调
+	maxEntries int                                  //容量上限,<=0表示不限制
+	lru        *list.List                           //lru链表,最近使用的在前面,maxEntries>0时才启用
+	keyFunc    KeyFunc                              //SetObject使用的key推导函数,未设置时用DefaultKeyFunc
 }
 
-func newCache(expiration time.Duration, interval time.Duration) *Cache {
+func newCache(expiration time.Duration, interval time.Duration, maxEntries ...int) *Cache {
 	c := &Cache{
 		items:   make(map[string]item),
 		janitor: newJanitor(interval),
 	}
+	if len(maxEntries) > 0 && maxEntries[0] > 0 {
+		c.maxEntries = maxEntries[0]
+		c.lru = list.New()
+	}
 	go c.janitor.run(c)
 	if expiration <= 0 {
 		c.expiration = 0
@@ -71,6 +122,22 @@ func newCache(expiration time.Duration, interval time.Duration) *Cache {
 
 func (this *Cache) getValue(key string) (interface{}, bool) {
 	now := time.Now().UnixNano()
+	if this.maxEntries > 0 {
+		this.mu.Lock()
+		v, ok := this.items[key]
+		if ok {
+			this.touchLRU(key, &v)
+			this.items[key] = v
+		}
+		this.mu.Unlock()
+		if ok {
+			if v.expiration > 0 && now > v.expiration {
+				return nil, false
+			}
+			return v.object, true
+		}
+		return nil, false
+	}
 	this.mu.RLock()
 	v, ok := this.items[key]
 	this.mu.RUnlock()
@@ -91,23 +158,140 @@ func (this *Cache) setValue(key string, value interface{}, expiration time.Durat
 	if expiration > 0 {
 		v.expiration = time.Now().Add(expiration).UnixNano()
 	}
+	evicted := this.setItemLocked(key, v)
+	cb := this.onEvicted
+	this.mu.Unlock()
+	if cb != nil {
+		for k, ev := range evicted {
+			cb(k, ev)
+		}
+	}
+}
+
+// touchLRU 在持有写锁的情况下维护lru链表:已有elem的移到表头,没有elem的(包括绕过setItemLocked直接写入items的历史数据)补建表头节点
+// 仅在maxEntries>0时生效,调用方需保证v与this.items中的条目是同一份数据
+func (this *Cache) touchLRU(key string, v *item) {
+	if this.maxEntries <= 0 {
+		return
+	}
+	if v.elem != nil {
+		this.lru.MoveToFront(v.elem)
+		return
+	}
+	v.elem = this.lru.PushFront(key)
+}
+
+// setItemLocked 在持有写锁的情况下写入一个条目,maxEntries>0时维护lru并在超出容量时淘汰最久未使用的条目
+// setValue/Add/Replace/Load/NewCacheGroupFrom经由此处写入以保证item.elem的不变式;
+// Increment/Decrement只改写已存在的条目,直接调用touchLRU后写回items,不经过这里
+func (this *Cache) setItemLocked(key string, v item) map[string]interface{} {
+	if this.maxEntries <= 0 {
+		this.items[key] = v
+		return nil
+	}
+	if old, ok := this.items[key]; ok && v.elem == nil {
+		v.elem = old.elem
+	}
+	this.touchLRU(key, &v)
 	this.items[key] = v
+	return this.evictOverflowLocked()
+}
+
+// evictOverflowLocked 在持有写锁的情况下淘汰最久未使用的条目直至不超过 maxEntries,返回被淘汰的条目供调用方在解锁后触发回调
+func (this *Cache) evictOverflowLocked() map[string]interface{} {
+	var evicted map[string]interface{}
+	for this.lru.Len() > this.maxEntries {
+		back := this.lru.Back()
+		if back == nil {
+			break
+		}
+		key := back.Value.(string)
+		v := this.items[key]
+		this.lru.Remove(back)
+		delete(this.items, key)
+		if evicted == nil {
+			evicted = make(map[string]interface{})
+		}
+		evicted[key] = v.object
+	}
+	return evicted
+}
+
+// Close 停止定时清理协程,之后Get/Set仍可正常使用,但过期条目不再被janitor自动清理
+func (this *Cache) Close() {
+	this.janitor.close()
+}
+
+// SetJanitorInterval 在运行期间调整定时清理的间隔
+func (this *Cache) SetJanitorInterval(d time.Duration) {
+	this.janitor.setInterval(d)
+}
+
+// OnEvicted 设置条目被清除(过期清理、Delete、Flush)时触发的回调
+// 回调在锁外执行,避免回调中重入缓存导致死锁
+func (this *Cache) OnEvicted(f func(key string, value interface{})) {
+	this.mu.Lock()
+	this.onEvicted = f
 	this.mu.Unlock()
 }
 
+// Delete 删除指定 key,若 key 不存在则返回 error
+func (this *Cache) Delete(key string) error {
+	this.mu.Lock()
+	v, ok := this.items[key]
+	if !ok {
+		this.mu.Unlock()
+		return errors.New("key not exist")
+	}
+	delete(this.items, key)
+	if this.maxEntries > 0 && v.elem != nil {
+		this.lru.Remove(v.elem)
+	}
+	cb := this.onEvicted
+	this.mu.Unlock()
+	if cb != nil {
+		cb(key, v.object)
+	}
+	return nil
+}
+
+// Flush 清空缓存中的所有条目,逐一触发回调
+func (this *Cache) Flush() {
+	this.mu.Lock()
+	items := this.items
+	cb := this.onEvicted
+	this.items = make(map[string]item)
+	if this.maxEntries > 0 {
+		this.lru = list.New()
+	}
+	this.mu.Unlock()
+	if cb != nil {
+		for k, v := range items {
+			cb(k, v.object)
+		}
+	}
+}
+
 type CacheGroup struct {
-	caches     map[string]*Cache //缓存组
-	expiration int64             //整个缓存过期时间
-	mu         sync.RWMutex      //锁 用于新增和删除map
-	keyLen     int8
+	caches     map[string]*Cache                   //缓存组
+	expiration int64                                //整个缓存过期时间
+	mu         sync.RWMutex                         //锁 用于新增和删除map
+	keyLen     int8                                 //key分割长度
+	onEvicted  func(key string, value interface{})  //应用到每个分片的回调
+	maxEntries int                                  //每个分片的容量上限,<=0表示不限制
+	keyFunc    KeyFunc                              //SetObject使用的key推导函数,未设置时用DefaultKeyFunc
 }
 
-func NewCacheGroup(expiration time.Duration) *CacheGroup {
-	return &CacheGroup{
+func NewCacheGroup(expiration time.Duration, maxEntries ...int) *CacheGroup {
+	cg := &CacheGroup{
 		caches:     make(map[string]*Cache),
 		expiration: time.Now().Add(expiration).UnixNano(),
 		keyLen:     defultKeyLen,
 	}
+	if len(maxEntries) > 0 && maxEntries[0] > 0 {
+		cg.maxEntries = maxEntries[0]
+	}
+	return cg
 }
 
 func (this *CacheGroup) addCache(key string, expiration time.Duration, interval time.Duration) {
@@ -117,7 +301,9 @@ func (this *CacheGroup) addCache(key string, expiration time.Duration, interval
 		this.mu.Unlock()
 		return
 	}
-	this.caches[key] = newCache(expiration, interval)
+	c := newCache(expiration, interval, this.maxEntries)
+	c.onEvicted = this.onEvicted
+	this.caches[key] = c
 	this.mu.Unlock()
 }
 
@@ -128,7 +314,8 @@ func (this *CacheGroup) addDefultCache(key string) *Cache {
 		this.mu.Unlock()
 		return v
 	}
-	c := newCache(defultExpiration, defultInterval)
+	c := newCache(defultExpiration, defultInterval, this.maxEntries)
+	c.onEvicted = this.onEvicted
 	this.caches[key] = c
 	//fmt.Println(key)
 	this.mu.Unlock()
@@ -170,6 +357,64 @@ func (this *CacheGroup) SetValue(key string, value interface{}, expiration time.
 	c.setValue(key, value, expiration)
 }
 
+// Close 停止缓存组内所有分片的定时清理协程
+func (this *CacheGroup) Close() {
+	this.mu.RLock()
+	caches := make([]*Cache, 0, len(this.caches))
+	for _, c := range this.caches {
+		caches = append(caches, c)
+	}
+	this.mu.RUnlock()
+	for _, c := range caches {
+		c.Close()
+	}
+}
+
+// SetJanitorInterval 调整缓存组内所有已存在分片的定时清理间隔
+func (this *CacheGroup) SetJanitorInterval(d time.Duration) {
+	this.mu.RLock()
+	caches := make([]*Cache, 0, len(this.caches))
+	for _, c := range this.caches {
+		caches = append(caches, c)
+	}
+	this.mu.RUnlock()
+	for _, c := range caches {
+		c.SetJanitorInterval(d)
+	}
+}
+
+// OnEvicted 为缓存组内所有已存在的分片设置回调,并应用到之后新建的分片
+func (this *CacheGroup) OnEvicted(f func(key string, value interface{})) {
+	this.mu.Lock()
+	this.onEvicted = f
+	for _, c := range this.caches {
+		c.OnEvicted(f)
+	}
+	this.mu.Unlock()
+}
+
+// Delete 删除某个 key,若其所在分片或 key 不存在则返回 error
+func (this *CacheGroup) Delete(key string) error {
+	c, ok := this.getCache(key)
+	if !ok {
+		return errors.New("key not exist")
+	}
+	return c.Delete(key)
+}
+
+// Flush 清空缓存组内所有分片的条目
+func (this *CacheGroup) Flush() {
+	this.mu.RLock()
+	caches := make([]*Cache, 0, len(this.caches))
+	for _, c := range this.caches {
+		caches = append(caches, c)
+	}
+	this.mu.RUnlock()
+	for _, c := range caches {
+		c.Flush()
+	}
+}
+
 func (this *CacheGroup) deleteCache(key string) error {
 	this.mu.Lock()
 	key = this.getSplitKey(key)
@@ -0,0 +1,199 @@
+package memory_cache
+
+import (
+	"encoding/gob"
+	"io"
+	"os"
+	"time"
+)
+
+// Item 是 item 的可导出形式,用于落盘和跨包访问
+type Item struct {
+	Object     interface{} //value
+	Expiration int64       //过期时间
+}
+
+func (this item) export() Item {
+	return Item{
+		Object:     this.object,
+		Expiration: this.expiration,
+	}
+}
+
+func fromItem(v Item) item {
+	return item{
+		object:     v.Object,
+		expiration: v.Expiration,
+	}
+}
+
+// Items 返回当前缓存中未过期条目的拷贝,调用方可自行序列化
+func (this *Cache) Items() map[string]Item {
+	now := time.Now().UnixNano()
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	m := make(map[string]Item, len(this.items))
+	for k, v := range this.items {
+		if v.expiration > 0 && v.expiration < now {
+			continue
+		}
+		m[k] = v.export()
+	}
+	return m
+}
+
+// Save 将缓存中未过期的条目以 gob 编码写入 w
+// 若 object 是自定义类型,调用方需提前用 gob.Register 注册,否则编解码会报错
+func (this *Cache) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(this.Items())
+}
+
+// SaveFile 将缓存保存到指定文件
+func (this *Cache) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := this.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load 从 r 中读取 gob 编码的条目并合并进缓存,已存在的 key 会被覆盖
+func (this *Cache) Load(r io.Reader) error {
+	items := make(map[string]Item)
+	if err := gob.NewDecoder(r).Decode(&items); err != nil {
+		return err
+	}
+	this.mu.Lock()
+	var evicted map[string]interface{}
+	for k, v := range items {
+		for ek, ev := range this.setItemLocked(k, fromItem(v)) {
+			if evicted == nil {
+				evicted = make(map[string]interface{})
+			}
+			evicted[ek] = ev
+		}
+	}
+	cb := this.onEvicted
+	this.mu.Unlock()
+	if cb != nil {
+		for k, v := range evicted {
+			cb(k, v)
+		}
+	}
+	return nil
+}
+
+// LoadFile 从指定文件中读取并合并进缓存
+func (this *Cache) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return this.Load(f)
+}
+
+// Items 返回缓存组中未过期条目的拷贝,按分片 key 分组
+func (this *CacheGroup) Items() map[string]map[string]Item {
+	this.mu.RLock()
+	caches := make(map[string]*Cache, len(this.caches))
+	for k, c := range this.caches {
+		caches[k] = c
+	}
+	this.mu.RUnlock()
+
+	m := make(map[string]map[string]Item, len(caches))
+	for k, c := range caches {
+		m[k] = c.Items()
+	}
+	return m
+}
+
+// Save 将缓存组中未过期的条目以 gob 编码写入 w
+func (this *CacheGroup) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(this.Items())
+}
+
+// SaveFile 将缓存组保存到指定文件
+func (this *CacheGroup) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := this.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load 从 r 中读取 gob 编码的分片数据并合并进缓存组,已存在的 key 会被覆盖
+func (this *CacheGroup) Load(r io.Reader) error {
+	shards := make(map[string]map[string]Item)
+	if err := gob.NewDecoder(r).Decode(&shards); err != nil {
+		return err
+	}
+	var fire []func()
+	this.mu.Lock()
+	for shardKey, items := range shards {
+		c, ok := this.caches[shardKey]
+		if !ok {
+			c = newCache(defultExpiration, defultInterval, this.maxEntries)
+			c.onEvicted = this.onEvicted
+			this.caches[shardKey] = c
+		}
+		c.mu.Lock()
+		var evicted map[string]interface{}
+		for k, v := range items {
+			for ek, ev := range c.setItemLocked(k, fromItem(v)) {
+				if evicted == nil {
+					evicted = make(map[string]interface{})
+				}
+				evicted[ek] = ev
+			}
+		}
+		cb := c.onEvicted
+		c.mu.Unlock()
+		if cb != nil && len(evicted) > 0 {
+			fire = append(fire, func() {
+				for k, v := range evicted {
+					cb(k, v)
+				}
+			})
+		}
+	}
+	this.mu.Unlock()
+	// 回调在缓存组的锁释放之后才触发,回调中重入缓存组不会死锁
+	for _, f := range fire {
+		f()
+	}
+	return nil
+}
+
+// LoadFile 从指定文件中读取并合并进缓存组
+func (this *CacheGroup) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return this.Load(f)
+}
+
+// NewCacheGroupFrom 用已有的分片数据构造缓存组,用于从快照恢复
+// maxEntries 与 NewCacheGroup 一致,用于还原每个分片的容量上限,不传则不限制
+func NewCacheGroupFrom(data map[string]map[string]Item, expiration time.Duration, maxEntries ...int) *CacheGroup {
+	cg := NewCacheGroup(expiration, maxEntries...)
+	for shardKey, items := range data {
+		c := newCache(defultExpiration, defultInterval, cg.maxEntries)
+		for k, v := range items {
+			c.setItemLocked(k, fromItem(v))
+		}
+		cg.caches[shardKey] = c
+	}
+	return cg
+}
@@ -0,0 +1,129 @@
+package memory_cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+)
+
+// point 是一个自定义类型,演示 item.object 为 interface{} 时,
+// 具体类型必须提前用 gob.Register 注册,否则 Save/Load 会失败
+type point struct {
+	X, Y int
+}
+
+func init() {
+	gob.Register(point{})
+}
+
+func TestCacheSaveLoadRoundTrip(t *testing.T) {
+	c := newCache(0, time.Hour)
+	c.setValue("str", "hello", 0)
+	c.setValue("num", 42, 0)
+	c.setValue("point", point{X: 1, Y: 2}, 0)
+
+	var buf bytes.Buffer
+	if err := c.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := newCache(0, time.Hour)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if v, ok := loaded.getValue("str"); !ok || v != "hello" {
+		t.Fatalf("expected str=hello, got %v, %v", v, ok)
+	}
+	if v, ok := loaded.getValue("num"); !ok || v != 42 {
+		t.Fatalf("expected num=42, got %v, %v", v, ok)
+	}
+	v, ok := loaded.getValue("point")
+	if !ok {
+		t.Fatalf("expected point to be present")
+	}
+	if p, ok := v.(point); !ok || p != (point{X: 1, Y: 2}) {
+		t.Fatalf("expected point{1,2}, got %v", v)
+	}
+}
+
+func TestCacheSaveLoadSkipsExpiredEntries(t *testing.T) {
+	c := newCache(0, time.Hour)
+	c.setValue("gone", "bye", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	c.setValue("stays", "hi", 0)
+
+	var buf bytes.Buffer
+	if err := c.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := newCache(0, time.Hour)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := loaded.getValue("gone"); ok {
+		t.Fatalf("expired entry should not survive a Save/Load round trip")
+	}
+	if v, ok := loaded.getValue("stays"); !ok || v != "hi" {
+		t.Fatalf("expected stays=hi, got %v, %v", v, ok)
+	}
+}
+
+func TestCacheGroupSaveLoadAndNewCacheGroupFrom(t *testing.T) {
+	cg := NewCacheGroup(time.Hour)
+	cg.SetValue("alpha", 1, 0)
+	cg.SetValue("beta", 2, 0)
+	cg.SetValue("gamma", 3, 0)
+
+	var buf bytes.Buffer
+	if err := cg.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	restored := NewCacheGroupFrom(cg.Items(), time.Hour)
+	if v, ok := restored.GetValue("alpha"); !ok || v != 1 {
+		t.Fatalf("expected alpha=1, got %v, %v", v, ok)
+	}
+	if v, ok := restored.GetValue("beta"); !ok || v != 2 {
+		t.Fatalf("expected beta=2, got %v, %v", v, ok)
+	}
+	if v, ok := restored.GetValue("gamma"); !ok || v != 3 {
+		t.Fatalf("expected gamma=3, got %v, %v", v, ok)
+	}
+
+	loaded := NewCacheGroup(time.Hour)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v, ok := loaded.GetValue("alpha"); !ok || v != 1 {
+		t.Fatalf("expected alpha=1 after Load, got %v, %v", v, ok)
+	}
+}
+
+// TestNewCacheGroupFromPreservesMaxEntries 验证maxEntries在快照恢复时被保留,
+// 而不是像修复前那样恢复成不限容量
+func TestNewCacheGroupFromPreservesMaxEntries(t *testing.T) {
+	cg := NewCacheGroup(time.Hour, 2)
+	// key的前3个字符相同,保证它们落在同一个分片上
+	cg.SetValue("keyA", 1, 0)
+
+	restored := NewCacheGroupFrom(cg.Items(), time.Hour, 2)
+	if restored.maxEntries != 2 {
+		t.Fatalf("expected restored.maxEntries=2, got %d", restored.maxEntries)
+	}
+
+	restored.SetValue("keyB", 2, 0)
+	restored.SetValue("keyC", 3, 0)
+	restored.GetValue("keyB")
+	restored.GetValue("keyC")
+	restored.SetValue("keyD", 4, 0)
+
+	if v, ok := restored.GetValue("keyA"); ok {
+		t.Fatalf("expected keyA to be evicted once its shard exceeded maxEntries=2, got %v", v)
+	}
+	if v, ok := restored.GetValue("keyD"); !ok || v != 4 {
+		t.Fatalf("expected keyD=4 to be present, got %v, %v", v, ok)
+	}
+}
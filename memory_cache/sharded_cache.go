@@ -0,0 +1,79 @@
+package memory_cache
+
+import (
+	"hash/fnv"
+	"runtime"
+	"time"
+)
+
+const defultShardCount int = 256 //默认分片数
+
+// ShardedCache 按 key 的 hash 值固定分片,分片数在构造时确定,
+// 避免 CacheGroup 按 key 前缀分片导致的分布不均
+type ShardedCache struct {
+	shards []*Cache //分片
+	n      uint32   //分片数
+}
+
+func fnv32(key string) uint32 {
+	h := fnv.New32()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// NewShardedCache 创建一个固定 shards 个分片的缓存,shards<=0 时使用默认值256
+func NewShardedCache(shards int, defaultExpiration, cleanupInterval time.Duration) *ShardedCache {
+	if shards <= 0 {
+		shards = defultShardCount
+	}
+	this := &ShardedCache{
+		shards: make([]*Cache, shards),
+		n:      uint32(shards),
+	}
+	for i := range this.shards {
+		this.shards[i] = newCache(defaultExpiration, cleanupInterval)
+	}
+	// 分片的janitor协程只持有*Cache,不会让ShardedCache保持可达,
+	// 因此可以直接在ShardedCache自身上挂finalizer,调用方忘记Close时也能回收
+	runtime.SetFinalizer(this, func(s *ShardedCache) {
+		s.Close()
+	})
+	return this
+}
+
+func (this *ShardedCache) getShard(key string) *Cache {
+	return this.shards[fnv32(key)%this.n]
+}
+
+func (this *ShardedCache) GetValue(key string) (interface{}, bool) {
+	return this.getShard(key).getValue(key)
+}
+
+func (this *ShardedCache) SetValue(key string, value interface{}, expiration time.Duration) {
+	this.getShard(key).setValue(key, value, expiration)
+}
+
+func (this *ShardedCache) Delete(key string) error {
+	return this.getShard(key).Delete(key)
+}
+
+// Close 停止所有分片的定时清理协程,之后Get/Set仍可正常使用
+func (this *ShardedCache) Close() {
+	for _, c := range this.shards {
+		c.Close()
+	}
+}
+
+// SetJanitorInterval 调整所有分片的定时清理间隔
+func (this *ShardedCache) SetJanitorInterval(d time.Duration) {
+	for _, c := range this.shards {
+		c.SetJanitorInterval(d)
+	}
+}
+
+// OnEvicted 为所有分片设置条目被清除时的回调
+func (this *ShardedCache) OnEvicted(f func(key string, value interface{})) {
+	for _, c := range this.shards {
+		c.OnEvicted(f)
+	}
+}
@@ -0,0 +1,90 @@
+package memory_cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestShardedCacheGetSetDelete 验证基本的读写删语义按分片正确路由
+func TestShardedCacheGetSetDelete(t *testing.T) {
+	sc := NewShardedCache(4, 0, time.Hour)
+	defer sc.Close()
+
+	sc.SetValue("a", 1, 0)
+	sc.SetValue("b", 2, 0)
+	if v, ok := sc.GetValue("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, got %v, %v", v, ok)
+	}
+	if v, ok := sc.GetValue("b"); !ok || v != 2 {
+		t.Fatalf("expected b=2, got %v, %v", v, ok)
+	}
+	if err := sc.Delete("a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := sc.GetValue("a"); ok {
+		t.Fatalf("expected a to be gone after Delete")
+	}
+	if err := sc.Delete("missing"); err == nil {
+		t.Fatalf("expected error deleting a missing key")
+	}
+}
+
+// TestShardedCacheDistributesAcrossShards 验证不同key按hash分布到不同分片,而不是全部落在一个分片
+func TestShardedCacheDistributesAcrossShards(t *testing.T) {
+	sc := NewShardedCache(8, 0, time.Hour)
+	defer sc.Close()
+
+	seen := make(map[*Cache]bool)
+	for i := 0; i < 64; i++ {
+		seen[sc.getShard(fmt.Sprintf("key-%d", i))] = true
+	}
+	if len(seen) < 2 {
+		t.Fatalf("expected keys to be distributed across multiple shards, got %d distinct shard(s)", len(seen))
+	}
+}
+
+// TestShardedCacheOnEvictedAppliesToAllShards 验证OnEvicted会应用到每一个分片
+func TestShardedCacheOnEvictedAppliesToAllShards(t *testing.T) {
+	sc := NewShardedCache(4, 0, time.Hour)
+	defer sc.Close()
+
+	evicted := make(map[string]interface{})
+	sc.OnEvicted(func(key string, value interface{}) {
+		evicted[key] = value
+	})
+
+	for i := 0; i < 8; i++ {
+		sc.SetValue(fmt.Sprintf("key-%d", i), i, 0)
+	}
+	for i := 0; i < 8; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if err := sc.Delete(key); err != nil {
+			t.Fatalf("Delete(%s) failed: %v", key, err)
+		}
+	}
+	if len(evicted) != 8 {
+		t.Fatalf("expected OnEvicted to fire for all 8 keys across shards, got %d", len(evicted))
+	}
+}
+
+// TestShardedCacheCloseStopsAllJanitors 验证Close之后缓存仍可正常读写,只是不再定时清理过期条目
+func TestShardedCacheCloseStopsAllJanitors(t *testing.T) {
+	sc := NewShardedCache(4, 0, time.Millisecond)
+	sc.SetValue("a", 1, time.Millisecond)
+	sc.Close()
+	// 每个分片的run()都在各自的stop关闭后返回
+	for i, c := range sc.shards {
+		select {
+		case <-c.janitor.stop:
+		default:
+			t.Fatalf("expected Close to close the janitor stop channel of shard %d", i)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	sc.SetValue("b", 2, 0)
+	if v, ok := sc.GetValue("b"); !ok || v != 2 {
+		t.Fatalf("expected cache to remain usable after Close, got %v, %v", v, ok)
+	}
+}
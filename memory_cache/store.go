@@ -0,0 +1,169 @@
+package memory_cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Store 是 Cache 和 CacheGroup 共同实现的存取接口,便于调用方以接口类型持有任意一种实现
+type Store interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, val interface{}, ttl time.Duration)
+	Delete(key string) error
+	Keys() []string
+	Len() int
+}
+
+var (
+	_ Store = (*Cache)(nil)
+	_ Store = (*CacheGroup)(nil)
+)
+
+// KeyFunc 从对象中推导出缓存 key,供 SetObject 使用
+type KeyFunc func(obj interface{}) (string, error)
+
+// DefaultKeyFunc 是未设置 KeyFunc 时使用的默认实现,直接取 obj 的字符串形式作为 key
+func DefaultKeyFunc(obj interface{}) (string, error) {
+	return fmt.Sprintf("%v", obj), nil
+}
+
+// NamespacedObject 是 MetaNamespaceKeyFunc 所需要的最小字段集合,类似k8s风格对象
+type NamespacedObject struct {
+	Namespace string
+	Name      string
+}
+
+// MetaNamespaceKeyFunc 为带 Namespace/Name 的对象生成 "namespace/name" 形式的 key,Namespace为空时只用Name
+func MetaNamespaceKeyFunc(obj interface{}) (string, error) {
+	switch v := obj.(type) {
+	case NamespacedObject:
+		return namespaceKey(v.Namespace, v.Name), nil
+	case *NamespacedObject:
+		return namespaceKey(v.Namespace, v.Name), nil
+	default:
+		return "", errors.New("object has no Namespace/Name")
+	}
+}
+
+func namespaceKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// Get 实现 Store 接口
+func (this *Cache) Get(key string) (interface{}, bool) {
+	return this.getValue(key)
+}
+
+// Set 实现 Store 接口
+func (this *Cache) Set(key string, val interface{}, ttl time.Duration) {
+	this.setValue(key, val, ttl)
+}
+
+// Keys 返回当前未过期的所有 key
+func (this *Cache) Keys() []string {
+	now := time.Now().UnixNano()
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	keys := make([]string, 0, len(this.items))
+	for k, v := range this.items {
+		if v.expiration > 0 && v.expiration < now {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Len 返回当前未过期的条目数
+func (this *Cache) Len() int {
+	return len(this.Keys())
+}
+
+// SetObject 用 keyFunc 推导出 key 后写入 obj,未设置 keyFunc 时使用 DefaultKeyFunc
+func (this *Cache) SetObject(obj interface{}, ttl time.Duration) error {
+	key, err := this.keyFuncOrDefault()(obj)
+	if err != nil {
+		return err
+	}
+	this.setValue(key, obj, ttl)
+	return nil
+}
+
+// SetKeyFunc 设置 SetObject 使用的 KeyFunc
+func (this *Cache) SetKeyFunc(f KeyFunc) {
+	this.mu.Lock()
+	this.keyFunc = f
+	this.mu.Unlock()
+}
+
+func (this *Cache) keyFuncOrDefault() KeyFunc {
+	this.mu.RLock()
+	f := this.keyFunc
+	this.mu.RUnlock()
+	if f == nil {
+		return DefaultKeyFunc
+	}
+	return f
+}
+
+// Get 实现 Store 接口
+func (this *CacheGroup) Get(key string) (interface{}, bool) {
+	return this.GetValue(key)
+}
+
+// Set 实现 Store 接口
+func (this *CacheGroup) Set(key string, val interface{}, ttl time.Duration) {
+	this.SetValue(key, val, ttl)
+}
+
+// Keys 返回缓存组内所有分片中未过期的 key
+func (this *CacheGroup) Keys() []string {
+	this.mu.RLock()
+	caches := make([]*Cache, 0, len(this.caches))
+	for _, c := range this.caches {
+		caches = append(caches, c)
+	}
+	this.mu.RUnlock()
+
+	var keys []string
+	for _, c := range caches {
+		keys = append(keys, c.Keys()...)
+	}
+	return keys
+}
+
+// Len 返回缓存组内未过期的条目总数
+func (this *CacheGroup) Len() int {
+	return len(this.Keys())
+}
+
+// SetObject 用 keyFunc 推导出 key 后写入 obj,未设置 keyFunc 时使用 DefaultKeyFunc
+func (this *CacheGroup) SetObject(obj interface{}, ttl time.Duration) error {
+	key, err := this.keyFuncOrDefault()(obj)
+	if err != nil {
+		return err
+	}
+	this.SetValue(key, obj, ttl)
+	return nil
+}
+
+// SetKeyFunc 设置 SetObject 使用的 KeyFunc
+func (this *CacheGroup) SetKeyFunc(f KeyFunc) {
+	this.mu.Lock()
+	this.keyFunc = f
+	this.mu.Unlock()
+}
+
+func (this *CacheGroup) keyFuncOrDefault() KeyFunc {
+	this.mu.RLock()
+	f := this.keyFunc
+	this.mu.RUnlock()
+	if f == nil {
+		return DefaultKeyFunc
+	}
+	return f
+}
@@ -0,0 +1,89 @@
+package memory_cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheStoreInterface(t *testing.T) {
+	c := newCache(0, time.Hour)
+	var s Store = c
+
+	s.Set("a", 1, 0)
+	if v, ok := s.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1 via Store.Get, got %v, %v", v, ok)
+	}
+	if s.Len() != 1 {
+		t.Fatalf("expected Len=1, got %d", s.Len())
+	}
+	keys := s.Keys()
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("expected Keys=[a], got %v", keys)
+	}
+	if err := s.Delete("a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if s.Len() != 0 {
+		t.Fatalf("expected Len=0 after Delete, got %d", s.Len())
+	}
+}
+
+func TestCacheSetObjectUsesDefaultKeyFunc(t *testing.T) {
+	c := newCache(0, time.Hour)
+	if err := c.SetObject(42, 0); err != nil {
+		t.Fatalf("SetObject failed: %v", err)
+	}
+	if v, ok := c.getValue("42"); !ok || v != 42 {
+		t.Fatalf("expected key \"42\" via DefaultKeyFunc, got %v, %v", v, ok)
+	}
+}
+
+func TestCacheSetObjectUsesMetaNamespaceKeyFunc(t *testing.T) {
+	c := newCache(0, time.Hour)
+	c.SetKeyFunc(MetaNamespaceKeyFunc)
+
+	obj := NamespacedObject{Namespace: "ns", Name: "foo"}
+	if err := c.SetObject(obj, 0); err != nil {
+		t.Fatalf("SetObject failed: %v", err)
+	}
+	if v, ok := c.getValue("ns/foo"); !ok || v != obj {
+		t.Fatalf("expected key \"ns/foo\", got %v, %v", v, ok)
+	}
+
+	if err := c.SetObject("not-namespaced", 0); err == nil {
+		t.Fatalf("expected MetaNamespaceKeyFunc to reject a non-namespaced object")
+	}
+}
+
+func TestMetaNamespaceKeyFuncEmptyNamespace(t *testing.T) {
+	key, err := MetaNamespaceKeyFunc(NamespacedObject{Name: "foo"})
+	if err != nil {
+		t.Fatalf("MetaNamespaceKeyFunc failed: %v", err)
+	}
+	if key != "foo" {
+		t.Fatalf("expected key \"foo\" with empty namespace, got %q", key)
+	}
+}
+
+func TestCacheGroupStoreInterfaceAndSetObject(t *testing.T) {
+	cg := NewCacheGroup(time.Hour)
+	var s Store = cg
+
+	s.Set("a", 1, 0)
+	if v, ok := s.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1 via Store.Get, got %v, %v", v, ok)
+	}
+
+	cg.SetKeyFunc(MetaNamespaceKeyFunc)
+	obj := NamespacedObject{Namespace: "ns", Name: "bar"}
+	if err := cg.SetObject(obj, 0); err != nil {
+		t.Fatalf("SetObject failed: %v", err)
+	}
+	if v, ok := cg.GetValue("ns/bar"); !ok || v != obj {
+		t.Fatalf("expected key \"ns/bar\", got %v, %v", v, ok)
+	}
+
+	if s.Len() != 2 {
+		t.Fatalf("expected Len=2, got %d", s.Len())
+	}
+}